@@ -0,0 +1,71 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Command autoupdate-report is a standalone CLI that checks a set of cached container
+// images for registry drift and prints the result, without running any experiments.
+// It mirrors `podman auto-update --dry-run`: point it at the image(s) you care about
+// and it tells you which ones have moved in their registry since they were last
+// pulled/built.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gvallee/container_validation_tool/pkg/autoupdate"
+)
+
+// images collects repeated -image path=url flags.
+type images []struct {
+	path string
+	url  string
+}
+
+func (i *images) String() string {
+	return fmt.Sprintf("%v", *i)
+}
+
+func (i *images) Set(value string) error {
+	path, url, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("invalid -image %q, expected <path>=<url>", value)
+	}
+	*i = append(*i, struct{ path, url string }{path, url})
+	return nil
+}
+
+func main() {
+	var imgs images
+	policy := flag.String("policy", string(autoupdate.PolicyRegistry), "drift check policy: registry, digest or off")
+	flag.Var(&imgs, "image", "cached image to check for drift, as <path>=<url>; may be repeated")
+	flag.Parse()
+
+	if len(imgs) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: autoupdate-report -image <path>=<url> [-image <path>=<url> ...] [-policy registry|digest|off]")
+		os.Exit(2)
+	}
+
+	var drifts []autoupdate.Drift
+	dirty := false
+	for _, img := range imgs {
+		drift, err := autoupdate.Check(img.path, img.url, autoupdate.Policy(*policy))
+		if err != nil {
+			log.Fatalf("failed to check %s for drift: %s", img.path, err)
+		}
+		if drift.Dirty() {
+			dirty = true
+		}
+		drifts = append(drifts, drift)
+	}
+
+	fmt.Print(autoupdate.Report(drifts))
+	if dirty {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Package autoupdate detects registry drift for cached MPI container images, mirroring
+// the semantics of `podman auto-update`: an image that has moved in its registry since
+// it was last pulled/built is flagged so the caller can invalidate stale results and
+// trigger a rebuild.
+package autoupdate
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+)
+
+// Policy controls whether, and how, an experiment's cached image is checked for drift.
+type Policy string
+
+const (
+	// PolicyRegistry re-resolves the tag/reference against the registry on every
+	// check, the same way `podman auto-update --policy registry` does.
+	PolicyRegistry Policy = "registry"
+	// PolicyDigest only flags drift when the image was pulled/built by digest and
+	// that digest has since been removed or superseded upstream.
+	PolicyDigest Policy = "digest"
+	// PolicyOff disables drift checking entirely.
+	PolicyOff Policy = "off"
+)
+
+// Drift captures the outcome of comparing a cached image against its registry digest.
+type Drift struct {
+	// Path is the local path to the cached image (e.g., a SIF file).
+	Path string
+	// URL is the registry reference the image was pulled/built from.
+	URL string
+	// StoredDigest is the digest recorded the last time the image was pulled/built, if
+	// any.
+	StoredDigest string
+	// RegistryDigest is the digest currently published for URL.
+	RegistryDigest string
+}
+
+// Dirty reports whether the cached image has fallen behind its registry reference.
+func (d Drift) Dirty() bool {
+	return d.StoredDigest != "" && d.StoredDigest != d.RegistryDigest
+}
+
+// sidecarPath returns the path to the digest sidecar file tracked alongside imagePath.
+func sidecarPath(imagePath string) string {
+	return imagePath + ".digest"
+}
+
+// StoredDigest reads the digest recorded the last time imagePath was pulled/built. It
+// returns an empty string, with no error, if no digest has been recorded yet.
+func StoredDigest(imagePath string) (string, error) {
+	data, err := os.ReadFile(sidecarPath(imagePath))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read digest sidecar for %s: %s", imagePath, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// RecordDigest persists digest as the last-known digest for imagePath, to be compared
+// against on the next drift check.
+func RecordDigest(imagePath string, digest string) error {
+	if err := os.WriteFile(sidecarPath(imagePath), []byte(digest), 0644); err != nil {
+		return fmt.Errorf("failed to write digest sidecar for %s: %s", imagePath, err)
+	}
+	return nil
+}
+
+// Check resolves the registry digest for url and compares it to the digest recorded
+// alongside imagePath the last time it was pulled or built.
+func Check(imagePath string, url string, policy Policy) (Drift, error) {
+	drift := Drift{Path: imagePath, URL: url}
+	if policy == PolicyOff {
+		return drift, nil
+	}
+
+	stored, err := StoredDigest(imagePath)
+	if err != nil {
+		return drift, err
+	}
+	drift.StoredDigest = stored
+
+	digest, err := crane.Digest(url)
+	if err != nil {
+		return drift, fmt.Errorf("failed to resolve registry digest for %s: %s", url, err)
+	}
+	drift.RegistryDigest = digest
+
+	return drift, nil
+}
+
+// Resolver maps a cached image path to the registry URL and update policy that should
+// be used to check it for drift. It returns an empty url to skip a path.
+type Resolver func(imagePath string) (url string, policy Policy)
+
+// Walk scans installDir for cached images and checks each one resolve identifies for
+// drift against its registry digest.
+func Walk(installDir string, resolve Resolver) ([]Drift, error) {
+	var drifts []Drift
+
+	err := filepath.Walk(installDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(path, ".digest") {
+			return nil
+		}
+
+		url, policy := resolve(path)
+		if url == "" || policy == PolicyOff {
+			return nil
+		}
+
+		drift, err := Check(path, url, policy)
+		if err != nil {
+			log.Printf("* failed to check %s for drift: %s", path, err)
+			return nil
+		}
+		drifts = append(drifts, drift)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %s", installDir, err)
+	}
+
+	return drifts, nil
+}
+
+// Report renders drifts as human-readable lines, one per checked image, for use by a
+// standalone "report drift" CLI entry point.
+func Report(drifts []Drift) string {
+	var b strings.Builder
+	for _, drift := range drifts {
+		status := "up to date"
+		if drift.Dirty() {
+			status = "DRIFTED"
+		}
+		fmt.Fprintf(&b, "%s (%s): %s [stored=%s registry=%s]\n", drift.Path, drift.URL, status, drift.StoredDigest, drift.RegistryDigest)
+	}
+	return b.String()
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package autoupdate
+
+import "testing"
+
+func TestDriftDirty(t *testing.T) {
+	tests := []struct {
+		name  string
+		drift Drift
+		want  bool
+	}{
+		{name: "no stored digest yet", drift: Drift{RegistryDigest: "sha256:abc"}, want: false},
+		{name: "matching digests", drift: Drift{StoredDigest: "sha256:abc", RegistryDigest: "sha256:abc"}, want: false},
+		{name: "drifted", drift: Drift{StoredDigest: "sha256:abc", RegistryDigest: "sha256:def"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.drift.Dirty(); got != tt.want {
+				t.Errorf("Drift{StoredDigest: %q, RegistryDigest: %q}.Dirty() = %v, want %v", tt.drift.StoredDigest, tt.drift.RegistryDigest, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"log"
+
+	"github.com/sylabs/singularity-mpi/pkg/sys"
+
+	"github.com/gvallee/container_validation_tool/pkg/autoupdate"
+)
+
+// AutoUpdate checks each experiment's cached container image for registry drift per its
+// UpdatePolicy, and drops the existingResults entries whose image has drifted so a
+// subsequent Pruning call reruns that experiment instead of trusting a stale result.
+// enabled is meant to be wired to a caller-owned flag (e.g. a CLI flag plumbed through
+// to main) rather than a field on sys.Config, which is vendored from
+// github.com/sylabs/singularity-mpi and not ours to extend. AutoUpdate is meant to be
+// called once before the main experiment loop; existingResults uses the local
+// ExperimentResult type, not results.Result, because it needs to compare per-platform
+// results and results.Result has no Platform field.
+func AutoUpdate(exps []ContainerConfig, existingResults []ExperimentResult, sysCfg *sys.Config, enabled bool) []ExperimentResult {
+	if !enabled {
+		return existingResults
+	}
+
+	type key struct {
+		hostVersion      string
+		containerVersion string
+		platform         string
+	}
+	dirty := make(map[key]bool)
+
+	for _, exp := range exps {
+		policy := exp.UpdatePolicy
+		if policy == "" {
+			policy = autoupdate.PolicyRegistry
+		}
+		if policy == autoupdate.PolicyOff {
+			continue
+		}
+
+		_, myContainerMPICfg, err := setExperimentCfg(exp, sysCfg, nil)
+		if err != nil {
+			log.Printf("* failed to resolve %s/%s for auto-update: %s", exp.HostMPI.Version, exp.ContainerMPI.Version, err)
+			continue
+		}
+
+		drift, err := autoupdate.Check(myContainerMPICfg.Container.Path, myContainerMPICfg.Container.URL, policy)
+		if err != nil {
+			log.Printf("* failed to check %s for drift: %s", myContainerMPICfg.Container.Path, err)
+			continue
+		}
+		if drift.Dirty() {
+			log.Printf("* %s has drifted from %s, marking dirty\n", myContainerMPICfg.Container.Path, myContainerMPICfg.Container.URL)
+			dirty[key{exp.HostMPI.Version, exp.ContainerMPI.Version, exp.Platform}] = true
+		}
+	}
+
+	if len(dirty) == 0 {
+		return existingResults
+	}
+
+	var freshResults []ExperimentResult
+	for _, result := range existingResults {
+		if dirty[key{result.HostMPI.Version, result.ContainerMPI.Version, result.Platform}] {
+			continue
+		}
+		freshResults = append(freshResults, result)
+	}
+	return freshResults
+}
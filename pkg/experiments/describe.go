@@ -0,0 +1,145 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gvallee/go_hpc_jobmgr/pkg/jm"
+	"github.com/sylabs/singularity-mpi/pkg/buildenv"
+	"github.com/sylabs/singularity-mpi/pkg/container"
+	"github.com/sylabs/singularity-mpi/pkg/sy"
+	"github.com/sylabs/singularity-mpi/pkg/sys"
+
+	"github.com/gvallee/container_validation_tool/pkg/runtime"
+)
+
+// Plan is the informational preflight produced by Describe: everything Run would do
+// for a given experiment, without touching the filesystem or spawning a process. It
+// mirrors the `oc new-app --dry-run` style preview: enough detail to validate a large
+// experiment matrix in seconds before committing hours of cluster time to it.
+type Plan struct {
+	// HostInstallSteps describes, in order, what Run would do to install HostMPI.
+	HostInstallSteps []string
+
+	// ContainerBuildSteps describes, in order, what Run would do to produce the
+	// container image, unless it is pulled instead (see ImageURL).
+	ContainerBuildSteps []string
+
+	// DefFilePreview previews the container this experiment would produce.
+	DefFilePreview string
+
+	// ImageURL is the resolved image reference Run would pull/build from.
+	ImageURL string
+
+	// ImageDigest is the platform-specific digest resolved from ImageURL, when the
+	// experiment targets a specific Platform and the base image is a manifest list.
+	ImageDigest string
+
+	// LauncherCmd previews the command line Run would hand to the job manager.
+	LauncherCmd string
+
+	// OutputFilename is the file Run would record results into.
+	OutputFilename string
+
+	// MissingBinaries lists required binaries that are not available on PATH. A
+	// non-empty list means the experiment cannot actually run on this host.
+	MissingBinaries []string
+}
+
+// Describe returns the Plan for exp: everything Run would do, without touching the
+// filesystem or spawning a process. Run's dryRun callers use this to validate a large
+// experiment matrix before committing to a real run.
+func Describe(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIToolConfig) (Plan, error) {
+	var plan Plan
+
+	plan.HostInstallSteps = []string{
+		fmt.Sprintf("build %s %s in %s", exp.HostMPI.ID, exp.HostMPI.Version, exp.HostBuildEnv.BuildDir),
+		fmt.Sprintf("install %s %s to %s", exp.HostMPI.ID, exp.HostMPI.Version, exp.HostBuildEnv.InstallDir),
+	}
+
+	containerName := container.GetContainerDefaultName(exp.Container.Distro, exp.ContainerMPI.ID, exp.ContainerMPI.Version, exp.App.Name, container.HybridModel) + containerNameSuffix(exp.Runtime)
+	containerPath := filepath.Join(exp.ContainerBuildEnv.InstallDir, containerName)
+	plan.DefFilePreview = fmt.Sprintf("%s def file for %s %s on %s, packaging %s, written to %s", exp.Runtime, exp.ContainerMPI.ID, exp.ContainerMPI.Version, exp.Container.Distro, exp.App.Name, containerPath)
+	plan.ContainerBuildSteps = []string{
+		fmt.Sprintf("generate container definition for %s %s", exp.ContainerMPI.ID, exp.ContainerMPI.Version),
+		fmt.Sprintf("build container image -> %s", containerPath),
+	}
+
+	plan.ImageURL = sy.GetImageURL(&exp.ContainerMPI, sysCfg)
+	if exp.Platform != "" {
+		digest, err := resolvePlatformImage(plan.ImageURL, exp.Platform)
+		if err != nil {
+			return plan, err
+		}
+		plan.ImageDigest = digest
+	}
+
+	jobmgr := jm.Detect()
+	plan.LauncherCmd = fmt.Sprintf("%s -- %s", jobmgr.BinPath, exp.App.Name)
+
+	err := GetOutputFilename(exp.ContainerMPI.ID, sysCfg)
+	if err != nil {
+		return plan, fmt.Errorf("failed to determine output filename: %s", err)
+	}
+	plan.OutputFilename = sysCfg.OutputFile
+
+	required := []string{runtimeBinary(exp.Runtime)}
+	if jobmgr.BinPath != "" {
+		required = append(required, jobmgr.BinPath)
+	}
+	required = append(required, compilers(exp.HostBuildEnv)...)
+	required = append(required, compilers(exp.ContainerBuildEnv)...)
+	plan.MissingBinaries = missingBinaries(required...)
+
+	return plan, nil
+}
+
+// runtimeBinary returns the binary Run would shell out to in order to build/pull/exec
+// exp's container, matching the dispatch in Run and runtime.Get.
+func runtimeBinary(name runtime.Name) string {
+	switch name {
+	case runtime.Podman:
+		return "podman"
+	case runtime.Docker:
+		return "docker"
+	default:
+		return "singularity"
+	}
+}
+
+// compilers extracts the compiler binaries buildenv.Info's Env requests (e.g.
+// "CC=mpicc"), so Describe can flag a missing toolchain the same way it flags a
+// missing runtime or launcher binary.
+func compilers(env buildenv.Info) []string {
+	var bins []string
+	for _, kv := range env.Env {
+		for _, prefix := range []string{"CC=", "CXX=", "FC=", "F77="} {
+			if strings.HasPrefix(kv, prefix) {
+				bins = append(bins, strings.TrimPrefix(kv, prefix))
+			}
+		}
+	}
+	return bins
+}
+
+// missingBinaries returns the subset of names that cannot be found on PATH.
+func missingBinaries(names ...string) []string {
+	var missing []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if _, err := exec.LookPath(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
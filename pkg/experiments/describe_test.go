@@ -0,0 +1,42 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sylabs/singularity-mpi/pkg/buildenv"
+
+	"github.com/gvallee/container_validation_tool/pkg/runtime"
+)
+
+func TestRuntimeBinary(t *testing.T) {
+	tests := []struct {
+		name runtime.Name
+		want string
+	}{
+		{name: "", want: "singularity"},
+		{name: runtime.Singularity, want: "singularity"},
+		{name: runtime.Podman, want: "podman"},
+		{name: runtime.Docker, want: "docker"},
+	}
+
+	for _, tt := range tests {
+		if got := runtimeBinary(tt.name); got != tt.want {
+			t.Errorf("runtimeBinary(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompilers(t *testing.T) {
+	env := buildenv.Info{Env: []string{"CC=mpicc", "CXX=mpicxx", "PATH=/usr/bin", "FC=mpifort"}}
+	want := []string{"mpicc", "mpicxx", "mpifort"}
+	if got := compilers(env); !reflect.DeepEqual(got, want) {
+		t.Errorf("compilers() = %v, want %v", got, want)
+	}
+}
@@ -7,6 +7,7 @@
 package experiments
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -25,6 +26,10 @@ import (
 	"github.com/sylabs/singularity-mpi/pkg/launcher"
 	"github.com/sylabs/singularity-mpi/pkg/sy"
 	"github.com/sylabs/singularity-mpi/pkg/sys"
+
+	"github.com/gvallee/container_validation_tool/pkg/autoupdate"
+	"github.com/gvallee/container_validation_tool/pkg/runtime"
+	"github.com/gvallee/container_validation_tool/pkg/trust"
 )
 
 // ContainerConfig is a structure that represents the configuration of an experiment
@@ -49,6 +54,33 @@ type ContainerConfig struct {
 
 	// Result gathers all the data related to the result of an experiment
 	Result results.Result
+
+	// Platforms lists the target architectures (e.g., "linux/amd64", "linux/arm64")
+	// this experiment should be expanded into via ExpandPlatforms. When empty, the
+	// experiment only targets the host's native platform.
+	Platforms []string
+
+	// Platform is the single architecture this experiment targets once expanded by
+	// ExpandPlatforms. It is left empty on the original, un-expanded declaration.
+	Platform string
+
+	// Runtime selects the container engine used to build, pull and execute the
+	// experiment's image (runtime.Singularity, runtime.Podman or runtime.Docker). An
+	// empty value defaults to runtime.Singularity, the tool's historical behavior.
+	Runtime runtime.Name
+
+	// Labels tags this experiment's result with arbitrary caller-defined key/value
+	// pairs (e.g., set per-entry by an expspec manifest), for filtering or grouping
+	// results after the fact. results.Result, which Result embeds, has no such field.
+	Labels map[string]string
+
+	// UpdatePolicy controls how AutoUpdate checks this experiment's cached image for
+	// registry drift. An empty value defaults to autoupdate.PolicyRegistry.
+	UpdatePolicy autoupdate.Policy
+
+	// TrustPolicy controls whether, and how, the experiment's container image is
+	// verified for signature and provenance before it is used.
+	TrustPolicy trust.Policy
 }
 
 // GetImplemFromExperiments returns the MPI implementation that is associated
@@ -116,11 +148,19 @@ func setExperimentCfg(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIT
 
 	myContainerMPICfg.Implem = exp.ContainerMPI
 	myContainerMPICfg.Buildenv = exp.ContainerBuildEnv
-	myContainerMPICfg.Container.Name = container.GetContainerDefaultName(exp.Container.Distro, exp.ContainerMPI.ID, exp.ContainerMPI.Version, exp.App.Name, container.HybridModel) + ".sif"
+	myContainerMPICfg.Container.Name = container.GetContainerDefaultName(exp.Container.Distro, exp.ContainerMPI.ID, exp.ContainerMPI.Version, exp.App.Name, container.HybridModel) + containerNameSuffix(exp.Runtime)
 	myContainerMPICfg.Container.Path = filepath.Join(myContainerMPICfg.Buildenv.InstallDir, myContainerMPICfg.Container.Name)
 	exp.Container.Path = myContainerMPICfg.Container.Path
 	myContainerMPICfg.Container.Model = container.HybridModel
 	myContainerMPICfg.Container.URL = sy.GetImageURL(&myContainerMPICfg.Implem, sysCfg)
+	if exp.Platform != "" {
+		resolvedURL, err := resolvePlatformImage(myContainerMPICfg.Container.URL, exp.Platform)
+		if err != nil {
+			return myHostMPICfg, myContainerMPICfg, err
+		}
+		myContainerMPICfg.Container.URL = resolvedURL
+		log.Printf("-> Target platform: %s (resolved image: %s)", exp.Platform, resolvedURL)
+	}
 	myContainerMPICfg.Container.BuildDir = myContainerMPICfg.Buildenv.BuildDir
 	myContainerMPICfg.Container.InstallDir = myContainerMPICfg.Buildenv.InstallDir
 	myContainerMPICfg.Container.Distro = exp.Container.Distro
@@ -161,14 +201,44 @@ func setExperimentCfg(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIT
 	return myHostMPICfg, myContainerMPICfg, nil
 }
 
-// Run configure, install and execute a given experiment
-func Run(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIToolConfig) (bool, results.Result, advexec.Result) {
-	var expRes results.Result
+// Run configure, install and execute a given experiment. dryRun, when true, makes Run
+// only produce and log the Describe plan instead of actually building/pulling/running
+// the experiment; it is a caller-owned flag (e.g. a CLI flag plumbed through to main)
+// rather than a field on sys.Config, which is vendored from
+// github.com/sylabs/singularity-mpi and not ours to extend.
+func Run(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIToolConfig, dryRun bool) (bool, ExperimentResult, advexec.Result) {
+	var expRes ExperimentResult
 	var execRes advexec.Result
+	expRes.HostMPI = exp.HostMPI
+	expRes.ContainerMPI = exp.ContainerMPI
+	expRes.Platform = exp.Platform
+
+	if dryRun {
+		plan, err := Describe(exp, sysCfg, syConfig)
+		if err != nil {
+			execRes.Err = fmt.Errorf("failed to describe experiment: %s", err)
+			expRes.Pass = false
+			return false, expRes, execRes
+		}
+		log.Printf("* Dry-run plan for %s on host / %s in container:\n%+v\n", exp.HostMPI.Version, exp.ContainerMPI.Version, plan)
+		if len(plan.MissingBinaries) > 0 {
+			execRes.Err = fmt.Errorf("missing required binaries: %v", plan.MissingBinaries)
+			expRes.Pass = false
+			return false, expRes, execRes
+		}
+		expRes.Pass = true
+		return false, expRes, execRes
+	}
 
 	/* Figure out details about the experiment's configuration */
 	myHostMPICfg, myContainerMPICfg, err := setExperimentCfg(exp, sysCfg, syConfig)
 	if err != nil {
+		if errors.Is(err, ErrUnsupportedPlatform) {
+			log.Printf("* %s, skipping...\n", err)
+			expRes.Pass = false
+			expRes.Note = err.Error()
+			return false, expRes, execRes
+		}
 		execRes.Err = fmt.Errorf("failed to set experiment's configuration: %s", err)
 		expRes.Pass = false
 		return false, expRes, execRes
@@ -205,30 +275,84 @@ func Run(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIToolConfig) (b
 	}
 
 	/* Prepare the container image */
-	if syConfig.BuildPrivilege || sysCfg.Nopriv {
-		if !util.PathExists(exp.Container.Path) {
-			execRes = createNewContainer(&myContainerMPICfg, exp, sysCfg, syConfig)
-			if execRes.Err != nil {
-				execRes.Err = fmt.Errorf("failed to create container: %s", err)
+	if exp.Runtime == "" || exp.Runtime == runtime.Singularity {
+		if syConfig.BuildPrivilege || sysCfg.Nopriv {
+			if !util.PathExists(exp.Container.Path) {
+				execRes = createNewContainer(&myContainerMPICfg, exp, sysCfg, syConfig)
+				if execRes.Err != nil {
+					execRes.Err = fmt.Errorf("failed to create container: %s", err)
+					expRes.Pass = false
+					return false, expRes, execRes
+				}
+			} else {
+				log.Printf("%s already exists, skipping build\n", exp.Container.Path)
+			}
+		} else {
+			err = container.PullContainerImage(&myContainerMPICfg.Container, &myContainerMPICfg.Implem, sysCfg, syConfig)
+			if err != nil {
+				execRes.Err = fmt.Errorf("failed to pull container: %s", err)
 				expRes.Pass = false
 				return false, expRes, execRes
 			}
-		} else {
-			log.Printf("%s already exists, skipping build\n", exp.Container.Path)
 		}
 	} else {
-		err = container.PullContainerImage(&myContainerMPICfg.Container, &myContainerMPICfg.Implem, sysCfg, syConfig)
+		rt, err := runtime.Get(exp.Runtime)
+		if err != nil {
+			execRes.Err = fmt.Errorf("failed to load %s runtime: %s", exp.Runtime, err)
+			expRes.Pass = false
+			return false, expRes, execRes
+		}
+		if syConfig.BuildPrivilege || sysCfg.Nopriv {
+			_, err = rt.Build(myContainerMPICfg.Container.Path, myContainerMPICfg.Buildenv.BuildDir)
+		} else {
+			_, err = rt.Pull(myContainerMPICfg.Container.URL)
+		}
 		if err != nil {
-			execRes.Err = fmt.Errorf("failed to pull container: %s", err)
+			execRes.Err = fmt.Errorf("failed to prepare container with the %s runtime: %s", exp.Runtime, err)
 			expRes.Pass = false
 			return false, expRes, execRes
 		}
 	}
 
+	/* Verify the container image's signature and provenance before using it */
+	var verification trust.Verification
+	if exp.Runtime == "" || exp.Runtime == runtime.Singularity {
+		verification, err = trust.VerifySIFImage(myContainerMPICfg.Container.Path, exp.TrustPolicy)
+	} else {
+		verification, err = trust.VerifyOCIImage(myContainerMPICfg.Container.URL, exp.TrustPolicy)
+	}
+	if err != nil {
+		execRes.Err = fmt.Errorf("image verification failed: %s", err)
+		if saveErr := launcher.SaveErrorDetails(&exp.HostMPI, &myContainerMPICfg.Implem, sysCfg, &execRes); saveErr != nil {
+			execRes.Err = fmt.Errorf("failed to save error details: %s", saveErr)
+		}
+		expRes.Pass = false
+		return false, expRes, execRes
+	}
 	/* Prepare the command to run the actual experiment */
 	log.Println("* Running Test(s)...")
 
-	expRes, execRes = launcher.Run(&exp.App, &myHostMPICfg, &exp.HostBuildEnv, &myContainerMPICfg, &jobmgr, sysCfg, nil)
+	if exp.Runtime == "" || exp.Runtime == runtime.Singularity {
+		expRes.Result, execRes = launcher.Run(&exp.App, &myHostMPICfg, &exp.HostBuildEnv, &myContainerMPICfg, &jobmgr, sysCfg, nil)
+	} else {
+		// The launcher package only knows how to drive a SIF-based MPI container; for
+		// the other runtimes, dispatch through the runtime interface instead so we
+		// never run a Singularity-oriented launcher against an image it didn't build.
+		rt, rtErr := runtime.Get(exp.Runtime)
+		if rtErr != nil {
+			execRes.Err = fmt.Errorf("failed to load %s runtime: %s", exp.Runtime, rtErr)
+			expRes.Pass = false
+			return false, expRes, execRes
+		}
+		image := runtime.Image{URL: myContainerMPICfg.Container.URL, Path: myContainerMPICfg.Container.Path}
+		execRes, err = rt.Exec(image, []string{exp.App.Name}, nil)
+		expRes.Pass = err == nil
+		if err != nil {
+			expRes.Note = err.Error()
+		}
+	}
+	expRes.VerifiedDigest = verification.Digest
+	expRes.Signer = verification.Signer
 	if !expRes.Pass {
 		return false, expRes, execRes
 	}
@@ -244,7 +368,7 @@ func Run(exp ContainerConfig, sysCfg *sys.Config, syConfig *sy.MPIToolConfig) (b
 
 	log.Printf("* Successful run - Analysing data...")
 
-	err = processOutput(&execRes, &expRes, &exp.App, sysCfg)
+	err = processOutput(&execRes, &expRes.Result, &exp.App, sysCfg)
 	if err != nil {
 		execRes.Err = fmt.Errorf("failed to process output: %s", err)
 		expRes.Pass = false
@@ -305,15 +429,15 @@ func createMPIContainer(appInfo *app.Info, mpiCfg *mpi.Config, env *buildenv.Inf
 }
 
 // Pruning removes the experiments for which we already have results
-func Pruning(experiments []ContainerConfig, existingResults []results.Result) []ContainerConfig {
+func Pruning(experiments []ContainerConfig, existingResults []ExperimentResult) []ContainerConfig {
 	// No optimization at the moment, double loop and creation of a new array
 	var experimentsToRun []ContainerConfig
 	//	for j := 0; j < len(experiments); j++ {
 	for _, experiment := range experiments {
 		found := false
 		for _, result := range existingResults {
-			if experiment.HostMPI.Version == result.HostMPI.Version && experiment.ContainerMPI.Version == result.ContainerMPI.Version {
-				log.Printf("We already have results for %s on the host and %s in a container, skipping...\n", experiment.HostMPI.Version, experiment.ContainerMPI.Version)
+			if experiment.HostMPI.Version == result.HostMPI.Version && experiment.ContainerMPI.Version == result.ContainerMPI.Version && experiment.Platform == result.Platform {
+				log.Printf("We already have results for %s on the host and %s in a container on platform %q, skipping...\n", experiment.HostMPI.Version, experiment.ContainerMPI.Version, experiment.Platform)
 				found = true
 				break
 			}
@@ -0,0 +1,129 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/gvallee/go_exec/pkg/results"
+	"github.com/gvallee/go_hpc_jobmgr/pkg/implem"
+)
+
+// ExperimentResult pairs the vendored, upstream results.Result (from
+// github.com/gvallee/go_exec/pkg/results, which this repo does not own and cannot add
+// fields to) with the per-experiment metadata that package has no field for, such as
+// Platform. Pruning and AutoUpdate operate on ExperimentResult rather than a bare
+// results.Result so that this comparison actually compiles and means something.
+type ExperimentResult struct {
+	results.Result
+
+	// HostMPI identifies the MPI implementation/version that was used on the host to
+	// produce this result, mirroring ContainerConfig.HostMPI so Pruning and AutoUpdate
+	// can match a result back to the experiment that produced it.
+	HostMPI implem.Info
+
+	// ContainerMPI identifies the MPI implementation/version that was used in the
+	// container to produce this result, mirroring ContainerConfig.ContainerMPI.
+	ContainerMPI implem.Info
+
+	// Platform is the architecture (see ContainerConfig.Platform) this result was
+	// produced for.
+	Platform string
+
+	// VerifiedDigest is the image digest verified by trust.Verify{OCI,SIF}Image
+	// before the experiment ran.
+	VerifiedDigest string
+
+	// Signer is the identity that produced the verified signature.
+	Signer string
+}
+
+// ErrUnsupportedPlatform is returned when an experiment targets a platform that cannot
+// be resolved to a child image on the current host, e.g. because the base image's
+// manifest list does not include that architecture.
+var ErrUnsupportedPlatform = errors.New("unsupported platform")
+
+// ExpandPlatforms fans a single experiment declaration with a Platforms matrix out into
+// one ContainerConfig per platform. Experiments that do not set Platforms are returned
+// unchanged so callers that only target the host's native architecture see no behavior
+// change.
+func ExpandPlatforms(experiments []ContainerConfig) []ContainerConfig {
+	var expanded []ContainerConfig
+	for _, experiment := range experiments {
+		if len(experiment.Platforms) == 0 {
+			expanded = append(expanded, experiment)
+			continue
+		}
+		for _, platform := range experiment.Platforms {
+			platformExperiment := experiment
+			platformExperiment.Platform = platform
+			expanded = append(expanded, platformExperiment)
+		}
+	}
+	return expanded
+}
+
+// resolvePlatformImage resolves imageURL to the child image digest matching platform
+// when imageURL points to a multi-arch manifest list. Single-arch references, or
+// experiments that do not request a specific platform, are returned unmodified.
+func resolvePlatformImage(imageURL string, platform string) (string, error) {
+	if platform == "" {
+		return imageURL, nil
+	}
+
+	os, arch, err := splitPlatform(platform)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := name.ParseReference(imageURL)
+	if err != nil {
+		// Not a reference we know how to resolve a manifest list for, leave as-is.
+		return imageURL, nil
+	}
+
+	desc, err := remote.Get(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch manifest for %s: %s", imageURL, err)
+	}
+	if !desc.MediaType.IsIndex() {
+		// Not a manifest list, nothing to resolve.
+		return imageURL, nil
+	}
+
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		return "", fmt.Errorf("failed to read image index for %s: %s", imageURL, err)
+	}
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return "", fmt.Errorf("failed to read index manifest for %s: %s", imageURL, err)
+	}
+
+	for _, m := range idxManifest.Manifests {
+		if m.Platform != nil && m.Platform.OS == os && m.Platform.Architecture == arch {
+			return fmt.Sprintf("%s@%s", ref.Context().Name(), m.Digest.String()), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s has no manifest for %s", ErrUnsupportedPlatform, imageURL, platform)
+}
+
+// splitPlatform splits a platform string such as "linux/arm64" into its OS and
+// architecture components.
+func splitPlatform(platform string) (string, string, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid platform %q, expected format <os>/<arch>", platform)
+	}
+	return parts[0], parts[1], nil
+}
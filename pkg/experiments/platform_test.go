@@ -0,0 +1,69 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"testing"
+
+	"github.com/gvallee/go_hpc_jobmgr/pkg/implem"
+)
+
+func TestSplitPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		wantOS   string
+		wantArch string
+		wantErr  bool
+	}{
+		{name: "valid", platform: "linux/amd64", wantOS: "linux", wantArch: "amd64"},
+		{name: "valid arm64", platform: "linux/arm64", wantOS: "linux", wantArch: "arm64"},
+		{name: "missing arch", platform: "linux", wantErr: true},
+		{name: "too many components", platform: "linux/arm/v7", wantErr: true},
+		{name: "empty", platform: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os, arch, err := splitPlatform(tt.platform)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitPlatform(%q) = nil error, want an error", tt.platform)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitPlatform(%q) returned unexpected error: %s", tt.platform, err)
+			}
+			if os != tt.wantOS || arch != tt.wantArch {
+				t.Errorf("splitPlatform(%q) = (%q, %q), want (%q, %q)", tt.platform, os, arch, tt.wantOS, tt.wantArch)
+			}
+		})
+	}
+}
+
+func TestExpandPlatforms(t *testing.T) {
+	noPlatforms := ContainerConfig{HostMPI: implem.Info{ID: "ompi", Version: "4.1.0"}}
+	multiPlatform := ContainerConfig{HostMPI: implem.Info{ID: "ompi", Version: "4.1.0"}, Platforms: []string{"linux/amd64", "linux/arm64"}}
+
+	expanded := ExpandPlatforms([]ContainerConfig{noPlatforms, multiPlatform})
+	if len(expanded) != 3 {
+		t.Fatalf("ExpandPlatforms returned %d entries, want 3", len(expanded))
+	}
+
+	if expanded[0].Platform != "" {
+		t.Errorf("unexpanded experiment got Platform %q, want empty", expanded[0].Platform)
+	}
+
+	gotPlatforms := []string{expanded[1].Platform, expanded[2].Platform}
+	wantPlatforms := []string{"linux/amd64", "linux/arm64"}
+	for i := range wantPlatforms {
+		if gotPlatforms[i] != wantPlatforms[i] {
+			t.Errorf("expanded[%d].Platform = %q, want %q", i+1, gotPlatforms[i], wantPlatforms[i])
+		}
+	}
+}
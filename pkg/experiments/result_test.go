@@ -0,0 +1,55 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"testing"
+
+	"github.com/gvallee/go_hpc_jobmgr/pkg/implem"
+)
+
+// TestExperimentResultEmbedding guards against ExperimentResult's promoted fields
+// (from the embedded results.Result) silently shadowing the verification metadata
+// this tool adds, since both are assigned independently throughout Run.
+func TestExperimentResultEmbedding(t *testing.T) {
+	var res ExperimentResult
+	res.Pass = true
+	res.Note = "all good"
+	res.Platform = "linux/arm64"
+	res.VerifiedDigest = "sha256:abc"
+	res.Signer = "did:example:123"
+
+	if !res.Pass || res.Note != "all good" {
+		t.Fatalf("embedded results.Result fields not set as expected: %+v", res.Result)
+	}
+	if res.Platform != "linux/arm64" || res.VerifiedDigest != "sha256:abc" || res.Signer != "did:example:123" {
+		t.Fatalf("ExperimentResult-local fields not set as expected: %+v", res)
+	}
+}
+
+// TestPruning exercises the HostMPI/ContainerMPI/Platform comparison Pruning relies on,
+// which only compiles once ExperimentResult actually carries those fields.
+func TestPruning(t *testing.T) {
+	done := ContainerConfig{
+		HostMPI:      implem.Info{Version: "4.1.0"},
+		ContainerMPI: implem.Info{Version: "4.1.0"},
+		Platform:     "linux/amd64",
+	}
+	pending := ContainerConfig{
+		HostMPI:      implem.Info{Version: "4.1.0"},
+		ContainerMPI: implem.Info{Version: "4.1.0"},
+		Platform:     "linux/arm64",
+	}
+	existingResults := []ExperimentResult{
+		{HostMPI: implem.Info{Version: "4.1.0"}, ContainerMPI: implem.Info{Version: "4.1.0"}, Platform: "linux/amd64"},
+	}
+
+	remaining := Pruning([]ContainerConfig{done, pending}, existingResults)
+	if len(remaining) != 1 || remaining[0].Platform != "linux/arm64" {
+		t.Fatalf("Pruning() = %+v, want only the linux/arm64 entry", remaining)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"github.com/gvallee/container_validation_tool/pkg/runtime"
+)
+
+// containerNameSuffix returns the filename suffix to use for an experiment's container
+// image, based on the runtime that will build/pull it. Singularity materializes a
+// single SIF file on disk; OCI runtimes (Podman, Docker) track images by reference in
+// their own store, so no file extension applies.
+func containerNameSuffix(name runtime.Name) string {
+	switch name {
+	case "", runtime.Singularity:
+		return ".sif"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package experiments
+
+import (
+	"testing"
+
+	"github.com/gvallee/container_validation_tool/pkg/runtime"
+)
+
+func TestContainerNameSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		rt   runtime.Name
+		want string
+	}{
+		{name: "empty defaults to singularity", rt: "", want: ".sif"},
+		{name: "singularity", rt: runtime.Singularity, want: ".sif"},
+		{name: "podman", rt: runtime.Podman, want: ""},
+		{name: "docker", rt: runtime.Docker, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := containerNameSuffix(tt.rt)
+			if got != tt.want {
+				t.Errorf("containerNameSuffix(%q) = %q, want %q", tt.rt, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Package expspec loads declarative, Kubernetes-Pod-inspired YAML manifests (akin to
+// `podman play kube`) describing a batch of experiments, and expands them into the
+// []experiments.ContainerConfig slice that experiments.Run consumes. It also offers
+// the reverse operation, so an ad-hoc run can be captured and replayed later.
+package expspec
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gvallee/container_validation_tool/pkg/experiments"
+	"github.com/gvallee/go_hpc_jobmgr/pkg/app"
+	"github.com/gvallee/go_hpc_jobmgr/pkg/implem"
+	"github.com/sylabs/singularity-mpi/pkg/container"
+)
+
+// ExperimentSet is the top-level document describing a batch of experiments.
+type ExperimentSet struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Spec       Spec   `yaml:"spec"`
+}
+
+// Spec is the body of an ExperimentSet document.
+type Spec struct {
+	// Matrix lists the dimensions to expand combinatorially into one ContainerConfig
+	// per combination.
+	Matrix Matrix `yaml:"matrix"`
+
+	// Prune, when true, is applied by callers via experiments.Pruning before running
+	// the expanded matrix.
+	Prune bool `yaml:"prune"`
+
+	// Persistent mirrors sys.Config.Persistent: experiments are not torn down after
+	// the run.
+	Persistent bool `yaml:"persistent"`
+
+	// Overrides lets a manifest override the build/install directories and result
+	// labels that would otherwise be computed by the caller for every entry, with an
+	// optional per-platform refinement for matrices that target more than one
+	// architecture.
+	Overrides Overrides `yaml:"overrides"`
+}
+
+// Matrix lists, per dimension, the values to expand combinatorially.
+type Matrix struct {
+	HostMPI      []MPIEntry `yaml:"hostMPI"`
+	ContainerMPI []MPIEntry `yaml:"containerMPI"`
+	Distro       []string   `yaml:"distro"`
+	App          []string   `yaml:"app"`
+	Platforms    []string   `yaml:"platforms,omitempty"`
+}
+
+// MPIEntry describes one MPI implementation/version pair in the matrix.
+type MPIEntry struct {
+	ID      string `yaml:"id"`
+	Version string `yaml:"version"`
+	URL     string `yaml:"url,omitempty"`
+}
+
+// Overrides captures the knobs a manifest can set instead of relying on
+// caller-computed defaults. OverrideValues is applied to every entry in the matrix,
+// then PerPlatform, keyed by the same platform strings as Matrix.Platforms, is applied
+// on top of it for that platform's entries only, so e.g. arm64 can get a different
+// installDir than amd64 within a single manifest. A PerPlatform entry only overrides
+// the fields it sets; anything left zero falls back to the base OverrideValues.
+type Overrides struct {
+	OverrideValues `yaml:",inline"`
+
+	PerPlatform map[string]OverrideValues `yaml:"perPlatform,omitempty"`
+}
+
+// OverrideValues is the set of per-entry knobs a manifest can pin, either globally
+// (Overrides.OverrideValues) or for a single platform (Overrides.PerPlatform).
+type OverrideValues struct {
+	BuildEnv BuildEnvOverrides `yaml:"buildEnv"`
+
+	// Labels tags every ContainerConfig this override applies to with these
+	// key/value pairs, propagated to experiments.ContainerConfig.Labels.
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// BuildEnvOverrides mirrors the subset of buildenv.Info a manifest is allowed to pin.
+type BuildEnvOverrides struct {
+	BuildDir   string `yaml:"buildDir,omitempty"`
+	InstallDir string `yaml:"installDir,omitempty"`
+}
+
+// Load reads and parses an ExperimentSet manifest at path, and expands its matrix into
+// one experiments.ContainerConfig per combination of hostMPI x containerMPI x distro x
+// app x platforms.
+func Load(path string) ([]experiments.ContainerConfig, *ExperimentSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	var set ExperimentSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+	if set.Kind != "ExperimentSet" {
+		return nil, nil, fmt.Errorf("unsupported kind %q, expected ExperimentSet", set.Kind)
+	}
+
+	return expand(set.Spec), &set, nil
+}
+
+// expand materializes a Spec's matrix into the combinatorial list of ContainerConfig
+// entries it describes, one entry per platform once Matrix.Platforms has more than one
+// value, so per-platform overrides can be applied to each resulting entry individually.
+func expand(spec Spec) []experiments.ContainerConfig {
+	var configs []experiments.ContainerConfig
+
+	distros := spec.Matrix.Distro
+	if len(distros) == 0 {
+		distros = []string{""}
+	}
+	apps := spec.Matrix.App
+	if len(apps) == 0 {
+		apps = []string{""}
+	}
+
+	for _, hostMPI := range spec.Matrix.HostMPI {
+		for _, containerMPI := range spec.Matrix.ContainerMPI {
+			for _, distro := range distros {
+				for _, appName := range apps {
+					configs = append(configs, experiments.ContainerConfig{
+						HostMPI:      implem.Info{ID: hostMPI.ID, Version: hostMPI.Version, URL: hostMPI.URL},
+						ContainerMPI: implem.Info{ID: containerMPI.ID, Version: containerMPI.Version, URL: containerMPI.URL},
+						Container:    container.Config{Distro: distro},
+						App:          app.Info{Name: appName},
+						Platforms:    spec.Matrix.Platforms,
+					})
+				}
+			}
+		}
+	}
+
+	configs = experiments.ExpandPlatforms(configs)
+	for i := range configs {
+		applyOverrides(&configs[i], spec.Overrides)
+	}
+
+	return configs
+}
+
+// applyOverrides sets cfg's build/install directories and labels from overrides,
+// refined by any PerPlatform entry matching cfg.Platform.
+func applyOverrides(cfg *experiments.ContainerConfig, overrides Overrides) {
+	values := overrides.OverrideValues
+	if platformValues, ok := overrides.PerPlatform[cfg.Platform]; ok {
+		if platformValues.BuildEnv.BuildDir != "" {
+			values.BuildEnv.BuildDir = platformValues.BuildEnv.BuildDir
+		}
+		if platformValues.BuildEnv.InstallDir != "" {
+			values.BuildEnv.InstallDir = platformValues.BuildEnv.InstallDir
+		}
+		if platformValues.Labels != nil {
+			values.Labels = platformValues.Labels
+		}
+	}
+
+	cfg.HostBuildEnv.BuildDir = values.BuildEnv.BuildDir
+	cfg.ContainerBuildEnv.BuildDir = values.BuildEnv.BuildDir
+	cfg.HostBuildEnv.InstallDir = values.BuildEnv.InstallDir
+	cfg.ContainerBuildEnv.InstallDir = values.BuildEnv.InstallDir
+	cfg.Labels = values.Labels
+}
+
+// Marshal emits a canonical ExperimentSet YAML document capturing an existing list of
+// ContainerConfig, so an ad-hoc run can be replayed later with Load.
+func Marshal(configs []experiments.ContainerConfig) ([]byte, error) {
+	set := ExperimentSet{
+		APIVersion: "containervalidationtool.gvallee.dev/v1",
+		Kind:       "ExperimentSet",
+	}
+
+	for _, cfg := range configs {
+		set.Spec.Matrix.HostMPI = appendUniqueMPIEntry(set.Spec.Matrix.HostMPI, cfg.HostMPI)
+		set.Spec.Matrix.ContainerMPI = appendUniqueMPIEntry(set.Spec.Matrix.ContainerMPI, cfg.ContainerMPI)
+		set.Spec.Matrix.Distro = appendUniqueString(set.Spec.Matrix.Distro, cfg.Container.Distro)
+		set.Spec.Matrix.App = appendUniqueString(set.Spec.Matrix.App, cfg.App.Name)
+		for _, platform := range cfg.Platforms {
+			set.Spec.Matrix.Platforms = appendUniqueString(set.Spec.Matrix.Platforms, platform)
+		}
+	}
+
+	data, err := yaml.Marshal(&set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal experiment set: %s", err)
+	}
+	return data, nil
+}
+
+func appendUniqueMPIEntry(entries []MPIEntry, info implem.Info) []MPIEntry {
+	entry := MPIEntry{ID: info.ID, Version: info.Version, URL: info.URL}
+	for _, existing := range entries {
+		if existing == entry {
+			return entries
+		}
+	}
+	return append(entries, entry)
+}
+
+func appendUniqueString(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package expspec
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandAppliesPerPlatformOverrides(t *testing.T) {
+	spec := Spec{
+		Matrix: Matrix{
+			HostMPI:      []MPIEntry{{ID: "openmpi", Version: "4.1.0"}},
+			ContainerMPI: []MPIEntry{{ID: "openmpi", Version: "4.1.0"}},
+			Platforms:    []string{"linux/amd64", "linux/arm64"},
+		},
+		Overrides: Overrides{
+			OverrideValues: OverrideValues{
+				BuildEnv: BuildEnvOverrides{BuildDir: "/build", InstallDir: "/install"},
+				Labels:   map[string]string{"tier": "default"},
+			},
+			PerPlatform: map[string]OverrideValues{
+				"linux/arm64": {
+					BuildEnv: BuildEnvOverrides{InstallDir: "/install-arm64"},
+					Labels:   map[string]string{"tier": "arm64"},
+				},
+			},
+		},
+	}
+
+	configs := expand(spec)
+	if len(configs) != 2 {
+		t.Fatalf("expand() returned %d configs, want 2", len(configs))
+	}
+
+	for _, cfg := range configs {
+		switch cfg.Platform {
+		case "linux/amd64":
+			if cfg.HostBuildEnv.InstallDir != "/install" || cfg.Labels["tier"] != "default" {
+				t.Errorf("linux/amd64 entry = %+v, want base overrides", cfg)
+			}
+			if cfg.HostBuildEnv.BuildDir != "/build" {
+				t.Errorf("linux/amd64 BuildDir = %q, want /build", cfg.HostBuildEnv.BuildDir)
+			}
+		case "linux/arm64":
+			if cfg.HostBuildEnv.InstallDir != "/install-arm64" || cfg.Labels["tier"] != "arm64" {
+				t.Errorf("linux/arm64 entry = %+v, want per-platform overrides", cfg)
+			}
+			// BuildDir has no per-platform override, so it should fall back to base.
+			if cfg.HostBuildEnv.BuildDir != "/build" {
+				t.Errorf("linux/arm64 BuildDir = %q, want base /build", cfg.HostBuildEnv.BuildDir)
+			}
+		default:
+			t.Errorf("unexpected platform %q", cfg.Platform)
+		}
+	}
+}
+
+func TestMarshalLoadRoundTrip(t *testing.T) {
+	configs := expand(Spec{
+		Matrix: Matrix{
+			HostMPI:      []MPIEntry{{ID: "openmpi", Version: "4.1.0"}},
+			ContainerMPI: []MPIEntry{{ID: "openmpi", Version: "4.1.0"}},
+			Distro:       []string{"ubuntu"},
+			App:          []string{"osu_benchmarks"},
+		},
+	})
+
+	data, err := Marshal(configs)
+	if err != nil {
+		t.Fatalf("Marshal() returned an error: %s", err)
+	}
+
+	var roundTripped ExperimentSet
+	if err := yaml.Unmarshal(data, &roundTripped); err != nil {
+		t.Fatalf("failed to unmarshal Marshal() output: %s", err)
+	}
+
+	if roundTripped.Kind != "ExperimentSet" {
+		t.Errorf("roundTripped.Kind = %q, want ExperimentSet", roundTripped.Kind)
+	}
+	if len(roundTripped.Spec.Matrix.HostMPI) != 1 || roundTripped.Spec.Matrix.HostMPI[0].ID != "openmpi" {
+		t.Errorf("roundTripped.Spec.Matrix.HostMPI = %+v, want one openmpi entry", roundTripped.Spec.Matrix.HostMPI)
+	}
+	if len(roundTripped.Spec.Matrix.Distro) != 1 || roundTripped.Spec.Matrix.Distro[0] != "ubuntu" {
+		t.Errorf("roundTripped.Spec.Matrix.Distro = %v, want [ubuntu]", roundTripped.Spec.Matrix.Distro)
+	}
+	if len(roundTripped.Spec.Matrix.App) != 1 || roundTripped.Spec.Matrix.App[0] != "osu_benchmarks" {
+		t.Errorf("roundTripped.Spec.Matrix.App = %v, want [osu_benchmarks]", roundTripped.Spec.Matrix.App)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gvallee/go_exec/pkg/advexec"
+)
+
+// DockerRuntime drives container lifecycle operations through the docker binary. Pull
+// is fully supported. Build and Exec (Dockerfile generation and the MPI transport
+// path) are not wired up yet and are tracked as follow-up work.
+type DockerRuntime struct{}
+
+// Build is not yet implemented for Docker: container_validation_tool has no Dockerfile
+// generator equivalent to the Singularity builder's GenerateDeffile, so there is
+// nothing valid to pass `docker build` yet. Returning early here is intentional: a
+// previous version of this method shelled out to `docker build` against a directory
+// path with no Dockerfile in it, which always failed.
+func (r *DockerRuntime) Build(defOrDockerfile string, ctx string) (Image, error) {
+	return Image{}, fmt.Errorf("building images under the docker runtime is not implemented yet")
+}
+
+// Pull fetches an OCI image into the local docker image store.
+func (r *DockerRuntime) Pull(ref string) (Image, error) {
+	var ae advexec.Advcmd
+	ae.BinPath = "docker"
+	ae.CmdArgs = []string{"pull", ref}
+	res := ae.Run()
+	if res.Err != nil {
+		return Image{}, fmt.Errorf("docker pull failed: %s", res.Err)
+	}
+	return Image{URL: ref}, nil
+}
+
+// Exec is not yet implemented for Docker: MPI transport over OCI runtimes is planned
+// but not available yet, see ContainerConfig.Runtime in pkg/experiments.
+func (r *DockerRuntime) Exec(image Image, argv []string, mounts []string) (advexec.Result, error) {
+	var res advexec.Result
+	res.Err = fmt.Errorf("MPI execution under the docker runtime is not implemented yet")
+	return res, res.Err
+}
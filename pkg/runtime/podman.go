@@ -0,0 +1,47 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gvallee/go_exec/pkg/advexec"
+)
+
+// PodmanRuntime drives container lifecycle operations through the podman binary. Pull
+// is fully supported. Build and Exec (Dockerfile generation and the MPI transport
+// path) are not wired up yet and are tracked as follow-up work.
+type PodmanRuntime struct{}
+
+// Build is not yet implemented for Podman: container_validation_tool has no
+// Dockerfile generator equivalent to the Singularity builder's GenerateDeffile, so
+// there is nothing valid to pass `podman build` yet. Returning early here is
+// intentional: a previous version of this method shelled out to `podman build`
+// against a directory path with no Dockerfile in it, which always failed.
+func (r *PodmanRuntime) Build(defOrDockerfile string, ctx string) (Image, error) {
+	return Image{}, fmt.Errorf("building images under the podman runtime is not implemented yet")
+}
+
+// Pull fetches an OCI image into the local podman image store.
+func (r *PodmanRuntime) Pull(ref string) (Image, error) {
+	var ae advexec.Advcmd
+	ae.BinPath = "podman"
+	ae.CmdArgs = []string{"pull", ref}
+	res := ae.Run()
+	if res.Err != nil {
+		return Image{}, fmt.Errorf("podman pull failed: %s", res.Err)
+	}
+	return Image{URL: ref}, nil
+}
+
+// Exec is not yet implemented for Podman: MPI transport over OCI runtimes is planned
+// but not available yet, see ContainerConfig.Runtime in pkg/experiments.
+func (r *PodmanRuntime) Exec(image Image, argv []string, mounts []string) (advexec.Result, error) {
+	var res advexec.Result
+	res.Err = fmt.Errorf("MPI execution under the podman runtime is not implemented yet")
+	return res, res.Err
+}
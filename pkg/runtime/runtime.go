@@ -0,0 +1,70 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Package runtime abstracts the container engine used to build, pull and execute the
+// images validated by container_validation_tool so that the rest of the codebase does
+// not need to know whether it is talking to Singularity, Podman or Docker.
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/gvallee/go_exec/pkg/advexec"
+)
+
+// Name identifies a supported container runtime backend.
+type Name string
+
+const (
+	// Singularity is the default runtime and the only one fully supported today.
+	Singularity Name = "singularity"
+	// Podman targets the Podman/OCI runtime. Build and Pull are supported; MPI
+	// transport over Exec is not wired up yet.
+	Podman Name = "podman"
+	// Docker targets the Docker/OCI runtime. Build and Pull are supported; MPI
+	// transport over Exec is not wired up yet.
+	Docker Name = "docker"
+)
+
+// Image represents a container image handled by a Runtime, regardless of whether it is
+// backed by a SIF file on disk or an OCI reference in a registry/local store.
+type Image struct {
+	// URL is the reference the image was built from or pulled from.
+	URL string
+	// Path is the local path to the image, when the runtime materializes one (e.g., a
+	// SIF file). It is empty for runtimes that only track images by reference/ID.
+	Path string
+	// Digest is the content digest of the resolved image, when known.
+	Digest string
+}
+
+// Runtime is implemented by each supported container engine. It is the single
+// extension point `experiments.Run` dispatches through, so adding a new backend never
+// requires changes to callers.
+type Runtime interface {
+	// Build creates an image from a definition file (Singularity) or Dockerfile (OCI
+	// runtimes) using ctx as the build context/working directory.
+	Build(defOrDockerfile string, ctx string) (Image, error)
+	// Pull fetches ref from its registry/library into the local image store.
+	Pull(ref string) (Image, error)
+	// Exec runs argv inside image, bind-mounting mounts into the container.
+	Exec(image Image, argv []string, mounts []string) (advexec.Result, error)
+}
+
+// Get returns the Runtime implementation for name. An empty name defaults to
+// Singularity to preserve the tool's historical behavior.
+func Get(name Name) (Runtime, error) {
+	switch name {
+	case "", Singularity:
+		return new(SingularityRuntime), nil
+	case Podman:
+		return new(PodmanRuntime), nil
+	case Docker:
+		return new(DockerRuntime), nil
+	default:
+		return nil, fmt.Errorf("unknown runtime %q", name)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gvallee/go_exec/pkg/advexec"
+)
+
+// SingularityRuntime drives container lifecycle operations through the singularity
+// binary on PATH. It is the backend container_validation_tool has always used.
+type SingularityRuntime struct{}
+
+// Build generates a SIF image from a Singularity definition file.
+func (r *SingularityRuntime) Build(defOrDockerfile string, ctx string) (Image, error) {
+	var ae advexec.Advcmd
+	ae.BinPath = "singularity"
+	ae.CmdArgs = []string{"build", ctx, defOrDockerfile}
+	res := ae.Run()
+	if res.Err != nil {
+		return Image{}, fmt.Errorf("singularity build failed: %s", res.Err)
+	}
+	return Image{Path: ctx}, nil
+}
+
+// Pull fetches a SIF image from a library:// or docker:// reference.
+func (r *SingularityRuntime) Pull(ref string) (Image, error) {
+	dest := strings.TrimSuffix(ref[strings.LastIndex(ref, "/")+1:], ".sif") + ".sif"
+	var ae advexec.Advcmd
+	ae.BinPath = "singularity"
+	ae.CmdArgs = []string{"pull", dest, ref}
+	res := ae.Run()
+	if res.Err != nil {
+		return Image{}, fmt.Errorf("singularity pull failed: %s", res.Err)
+	}
+	return Image{URL: ref, Path: dest}, nil
+}
+
+// Exec runs argv inside a SIF image via `singularity exec`, bind-mounting mounts.
+func (r *SingularityRuntime) Exec(image Image, argv []string, mounts []string) (advexec.Result, error) {
+	var ae advexec.Advcmd
+	ae.BinPath = "singularity"
+	ae.CmdArgs = []string{"exec"}
+	for _, m := range mounts {
+		ae.CmdArgs = append(ae.CmdArgs, "--bind", m)
+	}
+	ae.CmdArgs = append(ae.CmdArgs, image.Path)
+	ae.CmdArgs = append(ae.CmdArgs, argv...)
+	res := ae.Run()
+	return res, res.Err
+}
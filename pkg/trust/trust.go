@@ -0,0 +1,126 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Package trust verifies a container image's signature and provenance before an
+// experiment is allowed to use it: cosign/sigstore signatures for OCI references, and
+// Singularity PGP signatures for SIF files.
+package trust
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gvallee/go_exec/pkg/advexec"
+)
+
+// Policy describes how an image must be verified before an experiment is allowed to
+// use it.
+type Policy struct {
+	// RequireSignature, when true, fails verification if no valid signature is found.
+	// When false, Verify is a no-op.
+	RequireSignature bool
+
+	// Keys lists the public keys (local paths or KMS references) a signature is
+	// checked against. Empty means keyless (Fulcio/Rekor) verification.
+	Keys []string
+
+	// Fulcio is the Fulcio CA endpoint used for keyless OCI verification.
+	Fulcio string
+
+	// Rekor is the Rekor transparency log endpoint used for keyless OCI verification.
+	Rekor string
+}
+
+// Verification is the outcome of successfully verifying an image.
+type Verification struct {
+	// Digest is the content digest that was verified.
+	Digest string
+
+	// Signer is the identity (key fingerprint, or keyless certificate identity) that
+	// produced the verified signature.
+	Signer string
+}
+
+// VerifyOCIImage verifies ref, an OCI image reference, against policy via `cosign
+// verify`, and returns the verified digest and signer identity.
+func VerifyOCIImage(ref string, policy Policy) (Verification, error) {
+	if !policy.RequireSignature {
+		return Verification{}, nil
+	}
+
+	args := []string{"verify"}
+	for _, key := range policy.Keys {
+		args = append(args, "--key", key)
+	}
+	if len(policy.Keys) == 0 {
+		if policy.Fulcio != "" {
+			args = append(args, "--fulcio-url", policy.Fulcio)
+		}
+		if policy.Rekor != "" {
+			args = append(args, "--rekor-url", policy.Rekor)
+		}
+	}
+	args = append(args, ref)
+
+	var ae advexec.Advcmd
+	ae.BinPath = "cosign"
+	ae.CmdArgs = args
+	res := ae.Run()
+	if res.Err != nil {
+		return Verification{}, fmt.Errorf("cosign verify failed for %s: %s", ref, res.Err)
+	}
+
+	return Verification{Digest: ref, Signer: extractCosignSigner(res.Stdout)}, nil
+}
+
+// VerifySIFImage verifies path, a local SIF file, against policy via `singularity
+// verify` and its embedded PGP signatures.
+func VerifySIFImage(path string, policy Policy) (Verification, error) {
+	if !policy.RequireSignature {
+		return Verification{}, nil
+	}
+
+	args := []string{"verify"}
+	for _, key := range policy.Keys {
+		args = append(args, "--certificate", key)
+	}
+	args = append(args, path)
+
+	var ae advexec.Advcmd
+	ae.BinPath = "singularity"
+	ae.CmdArgs = args
+	res := ae.Run()
+	if res.Err != nil {
+		return Verification{}, fmt.Errorf("singularity verify failed for %s: %s", path, res.Err)
+	}
+
+	return Verification{Signer: extractSingularitySigner(res.Stdout)}, nil
+}
+
+// extractCosignSigner pulls the certificate/key identity out of `cosign verify`'s JSON
+// output, best-effort.
+func extractCosignSigner(stdout string) string {
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.Contains(line, "\"Subject\":") {
+			fields := strings.SplitN(line, ":", 2)
+			if len(fields) == 2 {
+				return strings.Trim(strings.TrimSpace(fields[1]), "\", ")
+			}
+		}
+	}
+	return ""
+}
+
+// extractSingularitySigner pulls the signer identity out of `singularity verify`'s
+// output, best-effort.
+func extractSingularitySigner(stdout string) string {
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.Contains(line, "Signed by") {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2019, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package trust
+
+import "testing"
+
+func TestExtractCosignSigner(t *testing.T) {
+	stdout := "{\n  \"critical\": {},\n  \"optional\": {\n    \"Subject\": \"user@example.com\"\n  }\n}\n"
+	if got, want := extractCosignSigner(stdout), "user@example.com"; got != want {
+		t.Errorf("extractCosignSigner() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractSingularitySigner(t *testing.T) {
+	stdout := "Verifying image: myimage.sif\nSigned by A1B2C3D4\n[OK]\n"
+	if got, want := extractSingularitySigner(stdout), "Signed by A1B2C3D4"; got != want {
+		t.Errorf("extractSingularitySigner() = %q, want %q", got, want)
+	}
+}
+
+func TestVerifyNoOpWhenSignatureNotRequired(t *testing.T) {
+	v, err := VerifyOCIImage("example.com/image:latest", Policy{RequireSignature: false})
+	if err != nil {
+		t.Fatalf("VerifyOCIImage returned unexpected error: %s", err)
+	}
+	if v != (Verification{}) {
+		t.Errorf("VerifyOCIImage() = %+v, want zero value when RequireSignature is false", v)
+	}
+}